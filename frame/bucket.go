@@ -0,0 +1,132 @@
+package frame
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	sgr "github.com/foize/go.sgr"
+)
+
+// Similarity controls how aggressively Buckets groups goroutines that
+// don't have byte-for-byte identical stacks.
+type Similarity int
+
+const (
+	// ExactLines groups goroutines with identical stacks, argument values
+	// included - the same behavior Dedup uses via FullMd5.
+	ExactLines Similarity = iota
+	// AnyPointer groups goroutines whose stacks only differ in
+	// pointer-looking argument values, e.g. two goroutines blocked on
+	// different *Mutex instances at the same call site.
+	AnyPointer
+	// AnyValue groups goroutines whose stacks match on function/file/line
+	// alone, ignoring every argument value.
+	AnyValue
+)
+
+// Bucket is a group of goroutines whose stacks are equal under some
+// Similarity, along with a summary of their wait times and states.
+type Bucket struct {
+	Signature string
+	IDs       []int
+	MinWait   int
+	MaxWait   int
+	AvgWait   int
+	States    map[string]bool
+}
+
+// Buckets groups the dump's goroutines by approximate stack equality and
+// returns them sorted by bucket size (descending), then by max wait time.
+// Unlike Dedup, it keeps the full count/wait distribution of each group.
+func (gd GoroutineDump) Buckets(similarity Similarity) []*Bucket {
+	bySig := map[string]*Bucket{}
+	order := make([]string, 0)
+
+	for _, g := range gd.goroutines {
+		sig := signature(g, similarity)
+		b, ok := bySig[sig]
+		if !ok {
+			b = &Bucket{
+				Signature: sig,
+				MinWait:   g.Duration,
+				MaxWait:   g.Duration,
+				States:    map[string]bool{},
+			}
+			bySig[sig] = b
+			order = append(order, sig)
+		}
+		b.IDs = append(b.IDs, g.Id)
+		b.States[g.Metas[MetaState]] = true
+		if g.Duration < b.MinWait {
+			b.MinWait = g.Duration
+		}
+		if g.Duration > b.MaxWait {
+			b.MaxWait = g.Duration
+		}
+		b.AvgWait += g.Duration
+	}
+
+	buckets := make([]*Bucket, 0, len(order))
+	for _, sig := range order {
+		b := bySig[sig]
+		if len(b.IDs) > 0 {
+			b.AvgWait /= len(b.IDs)
+		}
+		buckets = append(buckets, b)
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		if len(buckets[i].IDs) != len(buckets[j].IDs) {
+			return len(buckets[i].IDs) > len(buckets[j].IDs)
+		}
+		return buckets[i].MaxWait > buckets[j].MaxWait
+	})
+	return buckets
+}
+
+// PrintBuckets prints the bucket report for the given Similarity, sorted by
+// bucket size then wait time.
+func (gd GoroutineDump) PrintBuckets(similarity Similarity) {
+	buckets := gd.Buckets(similarity)
+	sgr.Printf("[fg-green]%d buckets from %d goroutines.[reset]\n\n", len(buckets), len(gd.goroutines))
+
+	for _, b := range buckets {
+		states := make([]string, 0, len(b.States))
+		for s := range b.States {
+			states = append(states, s)
+		}
+		sort.Strings(states)
+
+		sgr.Printf("[fg-red]%d[reset] goroutines, wait %d-%d (avg %d) min, states: %s\n",
+			len(b.IDs), b.MinWait, b.MaxWait, b.AvgWait, strings.Join(states, ", "))
+		fmt.Printf("%v\n\n", b.IDs)
+	}
+}
+
+// signature builds the string that two goroutines must share to land in
+// the same Bucket under the given Similarity.
+func signature(g *Goroutine, similarity Similarity) string {
+	if similarity == ExactLines {
+		return g.FullMd5
+	}
+
+	h := md5.New()
+	for _, call := range g.Frames {
+		io.WriteString(h, call.Func)
+		io.WriteString(h, call.SrcPath)
+		fmt.Fprintf(h, ":%d", call.Line)
+		if similarity == AnyValue {
+			continue
+		}
+		for _, arg := range call.Args {
+			if arg.looksLikePointer() {
+				continue
+			}
+			fmt.Fprintf(h, ",%d:%s", arg.Value, arg.Name)
+		}
+	}
+	return string(h.Sum(nil))
+}
@@ -0,0 +1,43 @@
+package frame
+
+import "testing"
+
+func mutexWaiter(id int, addr uint64) *Goroutine {
+	return &Goroutine{
+		Id:     id,
+		Frozen: true,
+		Frames: []Call{
+			{Func: "sync.(*Mutex).Lock", Args: []Arg{{Value: addr}}, SrcPath: "/tmp/worker.go", Line: 42},
+			{Func: "main.worker", SrcPath: "/tmp/worker.go", Line: 100},
+		},
+	}
+}
+
+func TestBucketsAnyPointerGroupsDifferentLockAddresses(t *testing.T) {
+	dump := NewGoroutineDump()
+	dump.Add(mutexWaiter(1, 0xc000010018))
+	dump.Add(mutexWaiter(2, 0xc000010030))
+
+	buckets := dump.Buckets(AnyPointer)
+	if len(buckets) != 1 {
+		t.Fatalf("len(buckets) = %d, want 1", len(buckets))
+	}
+	if len(buckets[0].IDs) != 2 {
+		t.Fatalf("len(buckets[0].IDs) = %d, want 2", len(buckets[0].IDs))
+	}
+}
+
+func TestBucketsExactLinesKeepsDifferentLockAddressesSeparate(t *testing.T) {
+	dump := NewGoroutineDump()
+	g1 := mutexWaiter(1, 0xc000010018)
+	g1.FullMd5 = "a"
+	g2 := mutexWaiter(2, 0xc000010030)
+	g2.FullMd5 = "b"
+	dump.Add(g1)
+	dump.Add(g2)
+
+	buckets := dump.Buckets(ExactLines)
+	if len(buckets) != 2 {
+		t.Fatalf("len(buckets) = %d, want 2", len(buckets))
+	}
+}
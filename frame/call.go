@@ -0,0 +1,162 @@
+package frame
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// callLinePattern matches the function line of a stack frame, e.g.
+	// `sync.(*Mutex).Lock(0xc000010018)` or `main.main()`.
+	callLinePattern = regexp.MustCompile(`^(.+)\((.*)\)$`)
+
+	// locationLinePattern matches the file/line/offset line that follows a
+	// call line, e.g. `\t/usr/local/go/src/sync/mutex.go:81 +0x115`.
+	locationLinePattern = regexp.MustCompile(`^\t(.+):(\d+)(?:\s+\+0x([0-9a-f]+))?$`)
+)
+
+// Arg is a single argument captured from a stack frame's call line. Value
+// holds the decoded hex value; Name holds anything that isn't one.
+type Arg struct {
+	Value uint64
+	Name  string
+}
+
+// looksLikePointer is a heuristic for whether a value is a pointer rather
+// than a small integer literal (length, flag, index, ...).
+func (a Arg) looksLikePointer() bool {
+	return a.Value > 0xffff
+}
+
+// Call is a single, structured stack frame.
+type Call struct {
+	Func     string
+	Args     []Arg
+	SrcPath  string
+	Line     int
+	IsStdlib bool
+
+	// LocalSrcPath is SrcPath remapped onto this host by a Context. Empty
+	// until a Context has been applied.
+	LocalSrcPath string
+
+	// Count is the number of consecutive, identical occurrences of this
+	// frame that were collapsed into this entry (e.g. recursive calls).
+	Count int
+}
+
+// parseCall turns a call line and its following location line into a Call.
+// It returns false if callLine doesn't look like a call line at all.
+func parseCall(callLine, locationLine string) (Call, bool) {
+	m := callLinePattern.FindStringSubmatch(callLine)
+	if m == nil {
+		return Call{}, false
+	}
+
+	fn := m[1]
+	if fn == "runtime.gopanic" {
+		// runtime.gopanic is how a panic() shows up in the dump; normalize
+		// it back to the name the user actually wrote.
+		fn = "panic"
+	}
+
+	call := Call{
+		Func:  fn,
+		Args:  parseArgs(m[2]),
+		Count: 1,
+	}
+
+	if lm := locationLinePattern.FindStringSubmatch(locationLine); lm != nil {
+		call.SrcPath = lm[1]
+		if line, err := strconv.Atoi(lm[2]); err == nil {
+			call.Line = line
+		}
+	}
+
+	call.IsStdlib = isStdlibCall(call)
+	return call, true
+}
+
+// parseArgs splits the comma-separated argument list of a call line into
+// Args. Elided arguments ("...") are kept as a named placeholder.
+func parseArgs(raw string) []Arg {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	parts := strings.Split(raw, ", ")
+	args := make([]Arg, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "..." {
+			args = append(args, Arg{Name: "..."})
+			continue
+		}
+		// The register ABI prints a trailing "?" on args whose value
+		// couldn't be recovered precisely (e.g. "0xc000032fa8?"); strip it
+		// before parsing so the value is still captured.
+		v, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(p, "0x"), "?"), 16, 64)
+		if err != nil {
+			// Not a hex pointer/value we recognize; keep it as a name so
+			// it isn't silently lost.
+			args = append(args, Arg{Name: p})
+			continue
+		}
+		args = append(args, Arg{Value: v})
+	}
+	return args
+}
+
+// isStdlibCall reports whether a frame belongs to the Go standard library
+// or to compiler-generated code, as opposed to user code.
+func isStdlibCall(c Call) bool {
+	if c.SrcPath == "<autogenerated>" {
+		return true
+	}
+	if strings.Contains(c.SrcPath, "/src/runtime/") {
+		return true
+	}
+	if strings.HasPrefix(c.Func, "runtime.") {
+		return true
+	}
+	// Standard library import paths have no dot in their first element, and
+	// their sources live under GOROOT/src/<import path>/.
+	pkg, _ := packageOf(c.Func)
+	first := strings.SplitN(pkg, "/", 2)[0]
+	return !strings.Contains(first, ".") && strings.Contains(c.SrcPath, "/src/"+pkg+"/")
+}
+
+// sameCall reports whether two Calls are identical ignoring Count, used to
+// collapse consecutive, identical frames (e.g. recursive calls).
+func sameCall(a, b Call) bool {
+	if a.Func != b.Func || a.SrcPath != b.SrcPath || a.Line != b.Line {
+		return false
+	}
+	if len(a.Args) != len(b.Args) {
+		return false
+	}
+	for i := range a.Args {
+		if a.Args[i] != b.Args[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// packageOf splits a fully-qualified function name into its package path
+// and the remaining function/method name, e.g.
+// "sync.(*Mutex).Lock" -> ("sync", "(*Mutex).Lock"), and
+// "github.com/foo/bar.Baz" -> ("github.com/foo/bar", "Baz").
+func packageOf(full string) (pkg, fn string) {
+	lastSlash := strings.LastIndex(full, "/")
+	rest := full[lastSlash+1:]
+	dot := strings.Index(rest, ".")
+	if dot == -1 {
+		return full, full
+	}
+	pkg = full[:lastSlash+1] + rest[:dot]
+	fn = rest[dot+1:]
+	return pkg, fn
+}
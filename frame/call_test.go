@@ -0,0 +1,59 @@
+package frame
+
+import "testing"
+
+func TestParseCall(t *testing.T) {
+	cases := []struct {
+		name       string
+		callLine   string
+		wantFunc   string
+		wantArgLen int
+	}{
+		{
+			name:       "pointer receiver method",
+			callLine:   "sync.(*Mutex).Lock(0xc0000100d0)",
+			wantFunc:   "sync.(*Mutex).Lock",
+			wantArgLen: 1,
+		},
+		{
+			name:       "plain function, no args",
+			callLine:   "main.main()",
+			wantFunc:   "main.main",
+			wantArgLen: 0,
+		},
+		{
+			name:       "register-uncertain arg",
+			callLine:   "sync.runtime_SemacquireMutex(0xc000032fa8?, 0x0, 0x1)",
+			wantFunc:   "sync.runtime_SemacquireMutex",
+			wantArgLen: 3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			call, ok := parseCall(c.callLine, "\t/usr/local/go/src/sync/mutex.go:81 +0x115")
+			if !ok {
+				t.Fatalf("parseCall(%q) returned false", c.callLine)
+			}
+			if call.Func != c.wantFunc {
+				t.Errorf("Func = %q, want %q", call.Func, c.wantFunc)
+			}
+			if len(call.Args) != c.wantArgLen {
+				t.Errorf("len(Args) = %d, want %d", len(call.Args), c.wantArgLen)
+			}
+		})
+	}
+}
+
+func TestParseArgsRegisterUncertain(t *testing.T) {
+	args := parseArgs("0xc000032fa8?, 0x0")
+	if len(args) != 2 {
+		t.Fatalf("len(args) = %d, want 2", len(args))
+	}
+	if args[0].Value != 0xc000032fa8 {
+		t.Errorf("args[0].Value = %#x, want 0xc000032fa8", args[0].Value)
+	}
+	if !args[0].looksLikePointer() {
+		t.Errorf("args[0] should look like a pointer")
+	}
+}
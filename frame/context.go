@@ -0,0 +1,111 @@
+package frame
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+var (
+	// runtimeSrcPattern captures the GOROOT a frame's runtime package was
+	// built from, e.g. "/home/build/go" out of
+	// "/home/build/go/src/runtime/proc.go".
+	runtimeSrcPattern = regexp.MustCompile(`^(.*)/src/runtime/`)
+
+	// gopathSrcPattern captures the GOPATH root out of the pre-modules
+	// "go get"-style layout, e.g. "/home/build/go" out of
+	// "/home/build/go/src/github.com/foo/bar/baz.go".
+	gopathSrcPattern = regexp.MustCompile(`^(.*)/src/([a-zA-Z0-9_.-]+\.[a-zA-Z]{2,}/[a-zA-Z0-9_.-]+)/`)
+
+	// gopathModPattern captures the GOPATH root out of the module-mode
+	// module cache layout, e.g. "/home/build/go" out of
+	// "/home/build/go/pkg/mod/github.com/foo/bar@v1.2.3/baz.go".
+	gopathModPattern = regexp.MustCompile(`^(.*)/pkg/mod/`)
+)
+
+// Context captures the GOROOT and GOPATH(s) a dump was built with, inferred
+// from its frames' source paths, and the mapping needed to resolve those
+// remote paths against the local machine.
+type Context struct {
+	GOROOT  string
+	GOPATHs map[string]string // remote GOPATH root -> local GOPATH root
+}
+
+// NewContext infers the GOROOT and GOPATH(s) used to build the binary that
+// produced dump by looking for the /src/runtime/, /src/<host>/<user>/, and
+// module-cache /pkg/mod/ layouts in its frames' source paths, then maps them
+// onto this host's runtime.GOROOT() and $GOPATH.
+func NewContext(dump *GoroutineDump) *Context {
+	ctx := &Context{GOPATHs: map[string]string{}}
+
+	goroots := map[string]int{}
+	gopaths := map[string]int{}
+
+	for _, g := range dump.goroutines {
+		for _, call := range g.Frames {
+			if m := runtimeSrcPattern.FindStringSubmatch(call.SrcPath); m != nil {
+				goroots[m[1]]++
+				continue
+			}
+			if m := gopathSrcPattern.FindStringSubmatch(call.SrcPath); m != nil {
+				gopaths[m[1]]++
+				continue
+			}
+			if m := gopathModPattern.FindStringSubmatch(call.SrcPath); m != nil {
+				gopaths[m[1]]++
+			}
+		}
+	}
+
+	ctx.GOROOT = mostCommonRoot(goroots)
+	for remoteRoot := range gopaths {
+		ctx.GOPATHs[remoteRoot] = localGOPATH()
+	}
+
+	return ctx
+}
+
+// Apply rewrites every frame's LocalSrcPath to the equivalent path on this
+// host, using the GOROOT/GOPATH mapping inferred by NewContext. Frames
+// whose remote root doesn't match anything known are left untouched.
+func (c *Context) Apply(dump *GoroutineDump) {
+	localRoot := runtime.GOROOT()
+
+	for _, g := range dump.goroutines {
+		for i, call := range g.Frames {
+			if c.GOROOT != "" && strings.HasPrefix(call.SrcPath, c.GOROOT+"/src/") {
+				g.Frames[i].LocalSrcPath = localRoot + strings.TrimPrefix(call.SrcPath, c.GOROOT)
+				continue
+			}
+			for remoteRoot, local := range c.GOPATHs {
+				if strings.HasPrefix(call.SrcPath, remoteRoot) {
+					g.Frames[i].LocalSrcPath = local + strings.TrimPrefix(call.SrcPath, remoteRoot)
+					break
+				}
+			}
+		}
+	}
+}
+
+// mostCommonRoot returns the key with the highest count.
+func mostCommonRoot(counts map[string]int) string {
+	best, bestCount := "", 0
+	for root, count := range counts {
+		if count > bestCount {
+			best, bestCount = root, count
+		}
+	}
+	return best
+}
+
+// localGOPATH returns the first entry of $GOPATH, falling back to the
+// default "$HOME/go" used when GOPATH is unset.
+func localGOPATH() string {
+	if gp := os.Getenv("GOPATH"); gp != "" {
+		return filepath.SplitList(gp)[0]
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "go")
+}
@@ -0,0 +1,23 @@
+package frame
+
+import "testing"
+
+func TestNewContextGopathModCache(t *testing.T) {
+	dump := NewGoroutineDump()
+	g := &Goroutine{
+		Frozen: true,
+		Frames: []Call{
+			{Func: "runtime.gopanic", SrcPath: "/home/build/go/src/runtime/panic.go", Line: 1},
+			{Func: "github.com/foo/bar.Baz", SrcPath: "/home/build/go/pkg/mod/github.com/foo/bar@v1.2.3/baz.go", Line: 10},
+		},
+	}
+	dump.Add(g)
+
+	ctx := NewContext(dump)
+	if ctx.GOROOT != "/home/build/go" {
+		t.Errorf("GOROOT = %q, want /home/build/go", ctx.GOROOT)
+	}
+	if _, ok := ctx.GOPATHs["/home/build/go"]; !ok {
+		t.Errorf("GOPATHs = %v, want an entry for /home/build/go (module cache)", ctx.GOPATHs)
+	}
+}
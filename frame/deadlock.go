@@ -0,0 +1,46 @@
+package frame
+
+import (
+	sgr "github.com/foize/go.sgr"
+)
+
+// Deadlocks reports the goroutines involved in a deadlock detected by the Go
+// runtime itself, i.e. a dump that was captured because the runtime printed
+// "fatal error: all goroutines are asleep - deadlock!" and then exited. That
+// message is a sound, generic signal: the runtime only emits it once it has
+// proven no goroutine can make progress, whether blocked on a Mutex, a
+// channel, a WaitGroup, or anything else. It returns nil if the dump wasn't
+// preceded by that banner.
+//
+// It does not reconstruct a wait-for graph itself by scanning each
+// goroutine's stack for frames showing which lock it already holds. That
+// doesn't work: once Lock() returns successfully its frame is
+// popped, so a goroutine that holds mu1 and is now blocked acquiring mu2
+// shows only mu2's address anywhere in its trace - there is no frame left to
+// find mu1 on. The same gap applies to channels: the "missing" send or
+// receive that would unblock a stuck goroutine is, by definition, never
+// reached by whichever goroutine would have executed it, so it leaves no
+// frame to correlate against either. Reconstructing "what does this
+// goroutine hold" from a single point-in-time stack dump isn't possible in
+// general; the runtime's own deadlock detector already solved this
+// correctly, so this just surfaces its verdict instead of re-deriving it.
+func (gd *GoroutineDump) Deadlocks() []*Goroutine {
+	if !gd.RuntimeDeadlock || gd.deadlockFrom > len(gd.goroutines) {
+		return nil
+	}
+	return append([]*Goroutine{}, gd.goroutines[gd.deadlockFrom:]...)
+}
+
+// PrintDeadlocks renders the goroutines reported by Deadlocks.
+func (gd *GoroutineDump) PrintDeadlocks() {
+	deadlocked := gd.Deadlocks()
+	if len(deadlocked) == 0 {
+		sgr.Println("[fg-green]No deadlocks detected.[reset]")
+		return
+	}
+
+	sgr.Printf("[fg-red]Deadlock[reset]: runtime reported all %d goroutines asleep\n", len(deadlocked))
+	for _, g := range deadlocked {
+		g.PrintWithColor()
+	}
+}
@@ -0,0 +1,98 @@
+package frame
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// This is the shape go1.21 actually prints for a textbook two-mutex
+// deadlock: each blocked goroutine's trace carries only the address of the
+// lock it's waiting on - the lock it already holds never appears anywhere
+// in its own stack, since Lock() pops its frame the moment it succeeds.
+const twoMutexDeadlockDump = `fatal error: all goroutines are asleep - deadlock!
+
+goroutine 6 [semacquire]:
+sync.runtime_SemacquireMutex(0xc000010018, 0x0, 0x1)
+	/usr/local/go/src/sync/sema.go:77 +0x25
+sync.(*Mutex).lockSlow(0xc000010018)
+	/usr/local/go/src/sync/mutex.go:171 +0x165
+sync.(*Mutex).Lock(...)
+	/usr/local/go/src/sync/mutex.go:90
+main.main.func1()
+	/tmp/deadlock.go:14 +0x65
+created by main.main in goroutine 1
+	/tmp/deadlock.go:12 +0x4c
+
+goroutine 7 [semacquire]:
+sync.runtime_SemacquireMutex(0xc000010020, 0x0, 0x1)
+	/usr/local/go/src/sync/sema.go:77 +0x25
+sync.(*Mutex).lockSlow(0xc000010020)
+	/usr/local/go/src/sync/mutex.go:171 +0x165
+sync.(*Mutex).Lock(...)
+	/usr/local/go/src/sync/mutex.go:90
+main.main.func2()
+	/tmp/deadlock.go:21 +0x65
+created by main.main in goroutine 1
+	/tmp/deadlock.go:19 +0x4c
+`
+
+func TestDeadlocksRuntimeReportedDump(t *testing.T) {
+	dump, err := ParseDump(strings.NewReader(twoMutexDeadlockDump), io.Discard)
+	if err != nil {
+		t.Fatalf("ParseDump: %v", err)
+	}
+	if !dump.RuntimeDeadlock {
+		t.Fatalf("RuntimeDeadlock = false, want true")
+	}
+
+	deadlocked := dump.Deadlocks()
+	if len(deadlocked) != 2 {
+		t.Fatalf("len(Deadlocks()) = %d, want 2", len(deadlocked))
+	}
+}
+
+func TestDeadlocksIgnoresEarlierUnrelatedDumpSection(t *testing.T) {
+	// A healthy SIGQUIT snapshot taken earlier in the same log, followed
+	// later by the real deadlock. ParseDump folds both into one
+	// GoroutineDump; only the second section's goroutines are deadlocked.
+	healthySnapshot := `goroutine 1 [running]:
+main.main()
+	/tmp/main.go:5 +0x10
+
+`
+	dump, err := ParseDump(strings.NewReader(healthySnapshot+twoMutexDeadlockDump), io.Discard)
+	if err != nil {
+		t.Fatalf("ParseDump: %v", err)
+	}
+	if len(dump.goroutines) != 3 {
+		t.Fatalf("len(goroutines) = %d, want 3", len(dump.goroutines))
+	}
+
+	deadlocked := dump.Deadlocks()
+	if len(deadlocked) != 2 {
+		t.Fatalf("len(Deadlocks()) = %d, want 2", len(deadlocked))
+	}
+	for _, g := range deadlocked {
+		if g.Id == 1 {
+			t.Fatalf("goroutine 1 from the earlier, unrelated snapshot was reported as deadlocked")
+		}
+	}
+}
+
+func TestDeadlocksNoRuntimeBanner(t *testing.T) {
+	const noDeadlock = `goroutine 1 [running]:
+main.main()
+	/tmp/main.go:5 +0x10
+`
+	dump, err := ParseDump(strings.NewReader(noDeadlock), io.Discard)
+	if err != nil {
+		t.Fatalf("ParseDump: %v", err)
+	}
+	if dump.RuntimeDeadlock {
+		t.Fatalf("RuntimeDeadlock = true, want false")
+	}
+	if deadlocked := dump.Deadlocks(); len(deadlocked) != 0 {
+		t.Fatalf("len(Deadlocks()) = %d, want 0", len(deadlocked))
+	}
+}
@@ -0,0 +1,81 @@
+package frame
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// startLinePattern matches the header line of a single goroutine, e.g.
+// `goroutine 42 [chan receive, 5 minutes]:`.
+var startLinePattern = regexp.MustCompile(`^goroutine\s+(\d+)\s+\[(.*)\]:$`)
+
+// runtimeDeadlockPattern matches the banner the Go runtime prints right
+// before dumping every goroutine once it has proven none of them can make
+// progress.
+var runtimeDeadlockPattern = regexp.MustCompile(`^fatal error: all goroutines are asleep - deadlock!$`)
+
+// ParseDump scans r for one or more goroutine dump sections and returns the
+// accumulated GoroutineDump. Anything that isn't part of a dump is copied to
+// out verbatim. Multiple back-to-back dumps (e.g. a process that received
+// SIGQUIT twice) are folded into the single returned GoroutineDump.
+func ParseDump(r io.Reader, out io.Writer) (*GoroutineDump, error) {
+	dump := NewGoroutineDump()
+	var goroutine *Goroutine
+
+	scanner := bufio.NewScanner(r)
+	// A single line of the input (e.g. a long log line around the dump) can
+	// exceed bufio's 64KB default token size; allow lines of up to 1MB.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case startLinePattern.MatchString(line):
+			if goroutine != nil {
+				goroutine.Freeze()
+			}
+			var err error
+			goroutine, err = NewGoroutine(line)
+			if err != nil {
+				return nil, err
+			}
+			dump.Add(goroutine)
+
+		case line == "" && goroutine != nil:
+			// End of a goroutine section.
+			goroutine.Freeze()
+			goroutine = nil
+
+		case goroutine != nil:
+			// AddLine tolerates lines it can't parse into a Call (e.g. the
+			// bare "goroutine running on other thread; stack unavailable"
+			// line), so nothing is dropped here.
+			goroutine.AddLine(line)
+
+		default:
+			if runtimeDeadlockPattern.MatchString(line) {
+				// The banner is immediately followed by the dump of the
+				// goroutines it refers to; only they - not any earlier,
+				// unrelated dump section already folded into this
+				// GoroutineDump - should be reported as deadlocked.
+				dump.RuntimeDeadlock = true
+				dump.deadlockFrom = len(dump.goroutines)
+			}
+			if out != nil {
+				fmt.Fprintln(out, line)
+			}
+		}
+	}
+
+	if goroutine != nil {
+		goroutine.Freeze()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
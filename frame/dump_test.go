@@ -0,0 +1,41 @@
+package frame
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseDumpMultipleSections(t *testing.T) {
+	input := `some log line before the crash
+goroutine 1 [running]:
+main.main()
+	/tmp/main.go:10 +0x10
+
+a line between two dumps
+goroutine 2 [chan receive, 5 minutes]:
+main.worker()
+	/tmp/main.go:20 +0x20
+
+goroutine 3 [syscall, locked to thread]:
+goroutine running on other thread; stack unavailable, locked to thread
+
+trailing log line
+`
+
+	var out bytes.Buffer
+	dump, err := ParseDump(strings.NewReader(input), &out)
+	if err != nil {
+		t.Fatalf("ParseDump: %v", err)
+	}
+	if len(dump.goroutines) != 3 {
+		t.Fatalf("len(goroutines) = %d, want 3", len(dump.goroutines))
+	}
+
+	if !strings.Contains(out.String(), "some log line before the crash") {
+		t.Errorf("non-dump lines weren't echoed to out: %q", out.String())
+	}
+	if strings.Contains(out.String(), "goroutine 1 [running]") {
+		t.Errorf("dump lines leaked into out: %q", out.String())
+	}
+}
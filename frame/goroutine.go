@@ -60,15 +60,17 @@ type Goroutine struct {
 	Duration int // In minutes.
 	Metas    map[MetaType]string
 
-	LineMd5      []string
-	FullMd5      string
-	FullHasher   hash.Hash
-	Duplicates   []ShortSlim
-	IsLockHolder bool
-	LockHolders  []string
-
-	Frozen bool
-	Buf    *bytes.Buffer
+	LineMd5    []string
+	FullMd5    string
+	FullHasher hash.Hash
+	Duplicates []ShortSlim
+
+	// Frames holds the structured, parsed stack, innermost frame first.
+	Frames []Call
+
+	Frozen    bool
+	Buf       *bytes.Buffer
+	pendingFn string
 }
 
 type ShortSlim struct {
@@ -93,28 +95,40 @@ func (g *Goroutine) AddLine(l string) {
 			g.LineMd5 = append(g.LineMd5, string(h.Sum(nil)))
 
 			io.WriteString(g.FullHasher, fl)
-		} else {
-			// caller
-			if !g.IsLockHolder && strings.Contains(l, ".Lock") {
-				g.IsLockHolder = true
-			}
-			if g.IsLockHolder {
-				// reg := regexp.MustCompile(`([\D\w.\-\/\(\*\)]+)\(([0x\w, ]+)\)`) // full function name
-				// caller := reg.FindStringSubmatch(l)
-				// if len(caller) == 3 {
-				// 	// caller[1]: caller
-				// 	// caller[2]: params
-				// }
-				reg := regexp.MustCompile(`(\*\w+)`) // only short Caller
-				caller := reg.FindStringSubmatch(l)
-				if len(caller) > 0 {
-					g.LockHolders = append(g.LockHolders, caller[0])
+
+			if g.pendingFn != "" {
+				if call, ok := parseCall(g.pendingFn, l); ok {
+					g.addFrame(call)
 				}
+				g.pendingFn = ""
 			}
+		} else {
+			// caller
+			g.pendingFn = l
 		}
 	}
 }
 
+// addFrame appends a parsed Call to Frames, collapsing it into the previous
+// frame's Count if it's identical (e.g. a recursive call).
+func (g *Goroutine) addFrame(call Call) {
+	if n := len(g.Frames); n > 0 && sameCall(g.Frames[n-1], call) {
+		g.Frames[n-1].Count++
+		return
+	}
+	g.Frames = append(g.Frames, call)
+}
+
+// TopFrame returns the innermost stack frame, i.e. where the goroutine is
+// currently blocked or executing. It returns the zero Call if the trace
+// couldn't be parsed into Frames.
+func (g Goroutine) TopFrame() Call {
+	if len(g.Frames) == 0 {
+		return Call{}
+	}
+	return g.Frames[0]
+}
+
 // Freeze freezes the goroutine info.
 func (g *Goroutine) Freeze() {
 	if !g.Frozen {
@@ -172,7 +186,27 @@ func (g Goroutine) PrintWithColor() {
 		sgr.Print("]")
 	}
 	sgr.Println()
-	fmt.Println(g.Trace)
+
+	if len(g.Frames) == 0 {
+		fmt.Println(g.Trace)
+		return
+	}
+	for _, call := range g.Frames {
+		path := call.SrcPath
+		if call.LocalSrcPath != "" {
+			path = call.LocalSrcPath
+		}
+		if call.IsStdlib {
+			sgr.Printf("[underline]%s[reset]\n", call.Func)
+		} else {
+			sgr.Printf("[fg-yellow]%s[reset]\n", call.Func)
+		}
+		if call.Count > 1 {
+			fmt.Printf("\t%s:%d (x%d)\n", path, call.Line, call.Count)
+		} else {
+			fmt.Printf("\t%s:%d\n", path, call.Line)
+		}
+	}
 }
 
 // NewGoroutine creates and returns a new Goroutine.
@@ -215,6 +249,17 @@ func NewGoroutine(metaline string) (*Goroutine, error) {
 // GoroutineDump defines a goroutine dump.
 type GoroutineDump struct {
 	goroutines []*Goroutine
+
+	// RuntimeDeadlock is true if the dump was preceded by the Go runtime's
+	// own "fatal error: all goroutines are asleep - deadlock!" banner.
+	RuntimeDeadlock bool
+
+	// deadlockFrom is the index into goroutines of the first goroutine that
+	// belongs to the dump section the banner actually precedes - since
+	// ParseDump can fold several back-to-back dump sections into one
+	// GoroutineDump, goroutines from an earlier, unrelated section must not
+	// be reported as part of the deadlock.
+	deadlockFrom int
 }
 
 // Add appends a goroutine info to the list.
@@ -429,13 +474,19 @@ func (gd *GoroutineDump) withCondition(cond string, callback func(int, *Goroutin
 
 	goroutines := make([]*Goroutine, 0, len(gd.goroutines))
 	for i, g := range gd.goroutines {
+		top := g.TopFrame()
+		pkg, _ := packageOf(top.Func)
 		params := map[string]interface{}{
-			"id":       g.Id,
-			"dups":     len(g.Duplicates),
-			"duration": g.Duration,
-			"lines":    g.Lines,
-			"state":    g.Metas[MetaState],
-			"trace":    g.Trace,
+			"id":        g.Id,
+			"dups":      len(g.Duplicates),
+			"duration":  g.Duration,
+			"lines":     g.Lines,
+			"state":     g.Metas[MetaState],
+			"trace":     g.Trace,
+			"func":      top.Func,
+			"file":      top.SrcPath,
+			"localfile": top.LocalSrcPath,
+			"pkg":       pkg,
 		}
 		res, err := expression.Evaluate(params)
 		if err != nil {
@@ -452,25 +503,3 @@ func (gd *GoroutineDump) withCondition(cond string, callback func(int, *Goroutin
 	fmt.Printf("Deleted %d goroutines, kept %d.\n", len(gd.goroutines)-len(goroutines), len(goroutines))
 	return goroutines, nil
 }
-
-func HasDeadLock(f1, f2 *Goroutine) bool {
-	if len(f1.LockHolders) < 1 || len(f2.LockHolders) < 1 {
-		return false
-	}
-	for idxi, hi1 := range f1.LockHolders {
-		for idxj, hj1 := range f2.LockHolders {
-			if hi1 == hj1 { // find the first equal pair
-				for j := idxj + 1; j < len(f2.LockHolders); j++ {
-					thj := f2.LockHolders[j]
-					for i := idxi - 1; i > 0; i-- {
-						thi := f1.LockHolders[i]
-						if thi == thj { // find the second equal pair
-							return true
-						}
-					}
-				}
-			}
-		}
-	}
-	return false
-}
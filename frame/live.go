@@ -0,0 +1,147 @@
+package frame
+
+import (
+	"crypto/md5"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"time"
+
+	sgr "github.com/foize/go.sgr"
+	"github.com/google/pprof/profile"
+)
+
+// liveFetchTimeout bounds a single FetchLive request so a stalled pprof
+// endpoint can't hang Watch forever.
+const liveFetchTimeout = 30 * time.Second
+
+var liveClient = &http.Client{Timeout: liveFetchTimeout}
+
+// FetchLive retrieves a live goroutine dump from a running process's
+// net/http/pprof endpoint, typically
+// "http://host:port/debug/pprof/goroutine?debug=2", and parses it with the
+// same stream parser used for on-disk dumps. It also accepts the binary
+// pprof profile served at "?debug=0".
+func FetchLive(url string) (*GoroutineDump, error) {
+	resp, err := liveClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", url, resp.Status)
+	}
+
+	if resp.Header.Get("Content-Type") == "application/octet-stream" {
+		return parseProfile(resp.Body)
+	}
+	return ParseDump(resp.Body, io.Discard)
+}
+
+// parseProfile decodes the binary pprof goroutine profile and synthesizes a
+// pseudo-Goroutine per sample. The state is reported as "unknown", but
+// Frames and the sample count are populated so Dedup/Buckets still work.
+func parseProfile(r io.Reader) (*GoroutineDump, error) {
+	p, err := profile.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dump := NewGoroutineDump()
+	ids := map[int]bool{}
+	for _, sample := range p.Sample {
+		g := &Goroutine{
+			Metas:  map[MetaType]string{MetaState: "unknown"},
+			Frozen: true,
+		}
+
+		h := md5.New()
+		for _, loc := range sample.Location {
+			for _, line := range loc.Line {
+				call := Call{
+					Func:    line.Function.Name,
+					SrcPath: line.Function.Filename,
+					Line:    int(line.Line),
+					Count:   1,
+				}
+				call.IsStdlib = isStdlibCall(call)
+				g.Frames = append(g.Frames, call)
+
+				io.WriteString(h, call.Func)
+				io.WriteString(h, call.SrcPath)
+				fmt.Fprintf(h, ":%d", call.Line)
+			}
+		}
+		g.FullMd5 = string(h.Sum(nil))
+
+		// The binary profile carries no goroutine ID, but Diff (used by
+		// Watch) keys purely on Id, so one is derived from the stack
+		// itself - stable across fetches as long as the stack doesn't
+		// change, unlike the sample's index into p.Sample.
+		g.Id = stableID(g.Frames, ids)
+		ids[g.Id] = true
+		g.Header = fmt.Sprintf("goroutine %d [unknown]:", g.Id)
+
+		if len(sample.Value) > 0 {
+			g.Lines = int(sample.Value[0])
+		}
+		g.Trace = fmt.Sprintf("%d samples", g.Lines)
+		dump.Add(g)
+	}
+	return dump, nil
+}
+
+// stableID derives a pseudo goroutine ID from a stack's frames by hashing
+// their func/file/line, so the same stack gets the same ID across separate
+// FetchLive calls. Collisions within a dump are resolved by linear probing.
+func stableID(frames []Call, taken map[int]bool) int {
+	h := fnv.New32a()
+	for _, call := range frames {
+		io.WriteString(h, call.Func)
+		io.WriteString(h, call.SrcPath)
+		fmt.Fprintf(h, ":%d", call.Line)
+	}
+	id := int(h.Sum32() & 0x7fffffff)
+	for taken[id] {
+		id++
+	}
+	return id
+}
+
+// Watch polls url every interval, diffing each fetch against the previous
+// one and printing the goroutines that showed up since. It runs until stop
+// is closed or a fetch fails.
+func Watch(url string, interval time.Duration, stop <-chan struct{}) error {
+	var prev *GoroutineDump
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		dump, err := FetchLive(url)
+		if err != nil {
+			return err
+		}
+
+		if prev != nil {
+			_, _, added := prev.Diff(dump)
+			if len(added.goroutines) == 0 {
+				sgr.Println("[fg-green]No new goroutines.[reset]")
+			} else {
+				sgr.Printf("[fg-red]%d new goroutine(s)[reset] since last fetch:\n", len(added.goroutines))
+				for _, g := range added.goroutines {
+					g.PrintWithColor()
+				}
+			}
+		}
+		prev = dump
+
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package frame
+
+import "testing"
+
+func TestStableIDIsDeterministicAndProbesCollisions(t *testing.T) {
+	frames := []Call{{Func: "main.worker", SrcPath: "/tmp/worker.go", Line: 10}}
+
+	first := stableID(frames, map[int]bool{})
+	second := stableID(frames, map[int]bool{})
+	if first != second {
+		t.Fatalf("stableID isn't deterministic: %d != %d", first, second)
+	}
+
+	probed := stableID(frames, map[int]bool{first: true})
+	if probed == first {
+		t.Fatalf("stableID didn't probe past a taken id")
+	}
+}
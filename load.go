@@ -1,18 +1,16 @@
 package main
 
 import (
-	"bufio"
 	"os"
-	"regexp"
 	"strings"
 
 	"github.com/shippomx/goins/frame"
 )
 
-var (
-	startLinePattern = regexp.MustCompile(`^goroutine\s+(\d+)\s+\[(.*)\]:$`)
-)
-
+// load reads a goroutine dump from fn. The file doesn't need to contain
+// only a well-formed dump - frame.ParseDump extracts the dump section(s)
+// from whatever surrounds them (log lines, crash banners, ...) and echoes
+// the rest to stdout so nothing is silently discarded.
 func load(fn string) (*frame.GoroutineDump, error) {
 	fn = strings.Trim(fn, "\"")
 	f, err := os.Open(fn)
@@ -21,35 +19,5 @@ func load(fn string) (*frame.GoroutineDump, error) {
 	}
 	defer f.Close()
 
-	dump := frame.NewGoroutineDump()
-	var goroutine *frame.Goroutine
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if startLinePattern.MatchString(line) {
-			goroutine, err = frame.NewGoroutine(line)
-			if err != nil {
-				return nil, err
-			}
-			dump.Add(goroutine)
-		} else if line == "" {
-			// End of a goroutine section.
-			if goroutine != nil {
-				goroutine.Freeze()
-			}
-			goroutine = nil
-		} else if goroutine != nil {
-			goroutine.AddLine(line)
-		}
-	}
-
-	if goroutine != nil {
-		goroutine.Freeze()
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-	return dump, nil
+	return frame.ParseDump(f, os.Stdout)
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/shippomx/goins/frame"
+)
+
+// main dispatches the goins subcommands. Each subcommand loads a dump, from
+// a file (buckets) or from a live pprof endpoint (fetch, watch), and renders
+// one of GoroutineDump's reports.
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "buckets":
+		err = runBuckets(os.Args[2:])
+	case "fetch":
+		err = runFetch(os.Args[2:])
+	case "watch":
+		err = runWatch(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: goins <command> [args]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  buckets <dump-file> [exact|pointer|value]")
+	fmt.Fprintln(os.Stderr, "  fetch <pprof-url>")
+	fmt.Fprintln(os.Stderr, "  watch <pprof-url> <interval>")
+}
+
+// runBuckets implements the "buckets" subcommand: load a dump and print its
+// Buckets report for the requested Similarity (AnyPointer by default).
+func runBuckets(args []string) error {
+	if len(args) < 1 {
+		usage()
+		os.Exit(1)
+	}
+
+	dump, err := load(args[0])
+	if err != nil {
+		return err
+	}
+
+	similarity := frame.AnyPointer
+	if len(args) > 1 {
+		switch args[1] {
+		case "exact":
+			similarity = frame.ExactLines
+		case "pointer":
+			similarity = frame.AnyPointer
+		case "value":
+			similarity = frame.AnyValue
+		default:
+			return fmt.Errorf("unknown similarity %q", args[1])
+		}
+	}
+
+	dump.PrintBuckets(similarity)
+	return nil
+}
+
+// runFetch implements the "fetch" subcommand: GET a live goroutine profile
+// and print it like any other dump.
+func runFetch(args []string) error {
+	if len(args) < 1 {
+		usage()
+		os.Exit(1)
+	}
+
+	dump, err := frame.FetchLive(args[0])
+	if err != nil {
+		return err
+	}
+	dump.Summary()
+	dump.Show(0, 1<<30)
+	return nil
+}
+
+// runWatch implements the "watch" subcommand: poll a live goroutine profile
+// every interval and print newly-appeared goroutines since the last fetch.
+func runWatch(args []string) error {
+	if len(args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	interval, err := time.ParseDuration(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid interval %q: %w", args[1], err)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		<-stop
+		close(done)
+	}()
+
+	return frame.Watch(args[0], interval, done)
+}